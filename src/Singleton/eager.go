@@ -0,0 +1,142 @@
+package singleton
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// eagerSlot holds the single eager instance of type T, guarded by once so
+// build only ever runs for the first caller and instance is safe to read
+// from any goroutine afterwards.
+type eagerSlot[T any] struct {
+	once     sync.Once
+	instance atomic.Pointer[T]
+}
+
+// eagerSlots holds one *eagerSlot[T] per distinct T, created lazily so
+// NewEager can be generic without a package-level generic variable, which
+// Go does not allow.
+var eagerSlots sync.Map // reflect.Type -> any (*eagerSlot[T])
+
+func eagerSlotFor[T any]() *eagerSlot[T] {
+	key := reflect.TypeOf((*T)(nil))
+	raw, _ := eagerSlots.LoadOrStore(key, &eagerSlot[T]{})
+	return raw.(*eagerSlot[T])
+}
+
+// NewEager eagerly runs build and stores the result in an atomic pointer,
+// mirroring the "hungry man" singleton variant: construction happens up
+// front instead of on first use. Like GetInstance, NewEager is keyless:
+// there is one eager instance per type T, and a second call (with any
+// build func) just returns the one already built. This trades a little
+// extra startup work (and memory that would otherwise never be allocated)
+// for zero-latency access later, and for determinism when other eager
+// singletons depend on the result. Prefer GetInstance's lazy sync.Once
+// when construction is expensive and might never be needed; prefer
+// NewEager when it's cheap, or when construction must happen in a known
+// order (see InitOrder). Use Register/RegisterE instead when a program
+// needs more than one named singleton of the same type.
+func NewEager[T any](build func() *T) *T {
+	slot := eagerSlotFor[T]()
+	slot.once.Do(func() {
+		slot.instance.Store(build())
+	})
+	return slot.instance.Load()
+}
+
+// MustEager is like NewEager but panics if build returns nil, for
+// singletons that must never be empty.
+func MustEager[T any](build func() *T) *T {
+	instance := NewEager(build)
+	if instance == nil {
+		panic("singleton: MustEager build returned nil")
+	}
+	return instance
+}
+
+// InitOrder lets a set of eager singletons declare dependencies on one
+// another and then builds them all in an order that respects those
+// dependencies, instead of relying on callers to hand-order their init()
+// functions. Add every node first, then call Resolve once.
+type InitOrder struct {
+	mu    sync.Mutex
+	nodes map[string]*initNode
+}
+
+type initNode struct {
+	dependsOn []string
+	build     func()
+}
+
+// NewInitOrder returns an empty InitOrder ready to have nodes Added to it.
+func NewInitOrder() *InitOrder {
+	return &InitOrder{nodes: make(map[string]*initNode)}
+}
+
+// Add registers a named eager singleton build step that Resolve will run
+// only after every name in dependsOn has already run.
+func (o *InitOrder) Add(name string, build func(), dependsOn ...string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.nodes[name] = &initNode{dependsOn: dependsOn, build: build}
+}
+
+// Resolve topologically sorts every registered node and runs each build
+// function exactly once, parents before children. It returns an error
+// instead of running anything if the dependency graph contains a cycle or
+// references a name that was never Added.
+func (o *InitOrder) Resolve() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(o.nodes))
+	order := make([]string, 0, len(o.nodes))
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("singleton: InitOrder cycle detected at %q", name)
+		}
+		node, ok := o.nodes[name]
+		if !ok {
+			return fmt.Errorf("singleton: InitOrder dependency %q was never added", name)
+		}
+		state[name] = visiting
+		for _, dep := range node.dependsOn {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		order = append(order, name)
+		return nil
+	}
+
+	// Sort names first so Resolve is deterministic across runs.
+	names := make([]string, 0, len(o.nodes))
+	for name := range o.nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range order {
+		o.nodes[name].build()
+	}
+	return nil
+}
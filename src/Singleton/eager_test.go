@@ -0,0 +1,76 @@
+package singleton
+
+import "testing"
+
+// NewEager is keyless (one instance per type), so each test below uses its
+// own type to avoid colliding with another test's eager singleton.
+type eagerWidget struct{ n int }
+type eagerNilWidget struct{}
+
+func TestNewEagerCachesInstance(t *testing.T) {
+	var calls int
+	first := NewEager(func() *eagerWidget {
+		calls++
+		return &eagerWidget{n: calls}
+	})
+	second := NewEager(func() *eagerWidget {
+		calls++
+		return &eagerWidget{n: calls}
+	})
+
+	if first != second {
+		t.Fatalf("NewEager returned different instances for the same type")
+	}
+	if calls != 1 {
+		t.Fatalf("build ran %d times, want 1", calls)
+	}
+}
+
+func TestMustEagerPanicsOnNil(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("MustEager did not panic on a nil build result")
+		}
+	}()
+	MustEager(func() *eagerNilWidget { return nil })
+}
+
+func TestInitOrderRunsParentsBeforeChildren(t *testing.T) {
+	var order []string
+	o := NewInitOrder()
+	o.Add("child", func() { order = append(order, "child") }, "parent")
+	o.Add("parent", func() { order = append(order, "parent") }, "grandparent")
+	o.Add("grandparent", func() { order = append(order, "grandparent") })
+
+	if err := o.Resolve(); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	want := []string{"grandparent", "parent", "child"}
+	if len(order) != len(want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+	for i, name := range want {
+		if order[i] != name {
+			t.Fatalf("order = %v, want %v", order, want)
+		}
+	}
+}
+
+func TestInitOrderDetectsCycle(t *testing.T) {
+	o := NewInitOrder()
+	o.Add("a", func() {}, "b")
+	o.Add("b", func() {}, "a")
+
+	if err := o.Resolve(); err == nil {
+		t.Fatal("Resolve() did not detect a dependency cycle")
+	}
+}
+
+func TestInitOrderRejectsMissingDependency(t *testing.T) {
+	o := NewInitOrder()
+	o.Add("a", func() {}, "missing")
+
+	if err := o.Resolve(); err == nil {
+		t.Fatal("Resolve() did not reject a dependency that was never added")
+	}
+}
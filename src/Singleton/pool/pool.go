@@ -0,0 +1,330 @@
+// Package pool layers a refcounted, idle-evicting resource manager on top
+// of the same once-only construction idea as GetInstance, for the common
+// real-world case this toy pattern is usually reached for: a shared DB
+// connection, a shared SSH client, or any other resource that's expensive
+// to open and safe to share. Each key has at most one current "generation"
+// of its resource at a time, built behind its own sync.Once; a failed
+// HealthCheck retires that generation (without closing it out from under
+// borrowers still holding it) and the next borrower builds a fresh one
+// under a new sync.Once. On top of that, instances are refcounted across
+// concurrent borrowers and closed down automatically once nobody has used
+// them for a while.
+package pool
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Option configures how a pooled resource behaves once it has been built.
+type Option[T any] func(*config[T])
+
+type config[T any] struct {
+	maxIdle     int
+	idleTimeout time.Duration
+	healthCheck func(*T) error
+	onEvict     func(*T)
+}
+
+// MaxIdle caps how many of the pool's keys may sit idle (refcount zero, not
+// yet evicted) at once. Once the cap is exceeded, the least-recently-idle
+// key is evicted immediately to make room, even if its IdleTimeout hasn't
+// elapsed yet. The default, 0, means no cap: idle entries are only evicted
+// by IdleTimeout (or immediately, if IdleTimeout is also unset).
+func MaxIdle[T any](n int) Option[T] {
+	return func(c *config[T]) { c.maxIdle = n }
+}
+
+// IdleTimeout delays eviction of an unreferenced resource by d, so a
+// borrower arriving shortly after the last one left can reuse it instead
+// of paying reconstruction cost.
+func IdleTimeout[T any](d time.Duration) Option[T] {
+	return func(c *config[T]) { c.idleTimeout = d }
+}
+
+// HealthCheck is invoked before an existing generation is handed out. If it
+// returns an error, that generation is retired: new borrowers get a fresh
+// generation built under a new sync.Once, while any borrower still holding
+// the retired one keeps it until they release it, at which point OnEvict
+// runs.
+func HealthCheck[T any](fn func(*T) error) Option[T] {
+	return func(c *config[T]) { c.healthCheck = fn }
+}
+
+// OnEvict runs whenever a generation of the resource is torn down, whether
+// from idle eviction, MaxIdle capping, or a failed HealthCheck.
+func OnEvict[T any](fn func(*T)) Option[T] {
+	return func(c *config[T]) { c.onEvict = fn }
+}
+
+// generation is one build of a key's resource. refs is how many borrowers
+// are currently holding it; stale means a failed HealthCheck has already
+// retired it in favor of a new generation, so its last closer must tear it
+// down instead of leaving it cached for reuse.
+type generation[T any] struct {
+	instance *T
+	refs     int
+	stale    bool
+}
+
+// entry tracks the current generation of one key's resource plus enough
+// bookkeeping to know when it's safe to close. buildOnce guards
+// construction of current only: retiring or evicting it replaces buildOnce
+// with a fresh sync.Once, so the next borrower reconstructs from scratch
+// instead of reusing a once that already fired.
+type entry[T any] struct {
+	mu        sync.Mutex
+	key       string
+	pool      evictNotifier
+	cfg       config[T]
+	buildOnce *sync.Once
+	buildErr  error
+	current   *generation[T]
+	timer     *time.Timer
+}
+
+// evictNotifier lets an entry tell its owning Pool that a key is no longer
+// idle once a timer eviction consumes it, without entry needing the full
+// generic Pool[T] type.
+type evictNotifier interface {
+	unmarkIdle(key string)
+}
+
+// evictCurrentLocked tears down the current generation (calling OnEvict)
+// and clears buildOnce so the next Get call builds a fresh one. The caller
+// must already hold e.mu.
+func (e *entry[T]) evictCurrentLocked() {
+	if e.timer != nil {
+		e.timer.Stop()
+		e.timer = nil
+	}
+	g := e.current
+	e.current = nil
+	e.buildOnce = nil
+	if g == nil || g.instance == nil {
+		return
+	}
+	instance := g.instance
+	g.instance = nil
+	if e.cfg.onEvict != nil {
+		e.cfg.onEvict(instance)
+	}
+}
+
+// scheduleEvictionLocked decides what happens to the current generation
+// the instant it goes unreferenced. With IdleTimeout set, it stays cached
+// until the timer fires. With MaxIdle set (but no IdleTimeout), it stays
+// cached indefinitely and Pool.markIdle decides when it must go to stay
+// under the cap. With neither configured, there is nothing keeping it
+// around, so it is evicted immediately. The caller must already hold e.mu.
+func (e *entry[T]) scheduleEvictionLocked() {
+	g := e.current
+	switch {
+	case e.cfg.idleTimeout > 0:
+		e.timer = time.AfterFunc(e.cfg.idleTimeout, func() {
+			e.mu.Lock()
+			evicted := e.current == g && g.refs <= 0
+			if evicted {
+				e.evictCurrentLocked()
+			}
+			e.mu.Unlock()
+			if evicted {
+				e.pool.unmarkIdle(e.key)
+			}
+		})
+	case e.cfg.maxIdle > 0:
+		// Left cached; capped by Pool.markIdle instead of a timer.
+	default:
+		e.evictCurrentLocked()
+	}
+}
+
+// Pool manages the pooled resources for every key of type T.
+type Pool[T any] struct {
+	mu      sync.Mutex
+	entries map[string]*entry[T]
+	idle    []string // keys with refs <= 0, oldest-idle-first
+}
+
+func (p *Pool[T]) markIdle(key string, maxIdle int) {
+	p.mu.Lock()
+	alreadyIdle := false
+	for _, k := range p.idle {
+		if k == key {
+			alreadyIdle = true
+			break
+		}
+	}
+	if !alreadyIdle {
+		p.idle = append(p.idle, key)
+	}
+	var evict []string
+	if maxIdle > 0 {
+		for len(p.idle) > maxIdle {
+			evict = append(evict, p.idle[0])
+			p.idle = p.idle[1:]
+		}
+	}
+	p.mu.Unlock()
+
+	for _, k := range evict {
+		if e, ok := p.lookup(k); ok {
+			e.mu.Lock()
+			if e.current != nil && e.current.refs <= 0 {
+				e.evictCurrentLocked()
+			}
+			e.mu.Unlock()
+		}
+	}
+}
+
+func (p *Pool[T]) unmarkIdle(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, k := range p.idle {
+		if k == key {
+			p.idle = append(p.idle[:i], p.idle[i+1:]...)
+			return
+		}
+	}
+}
+
+func (p *Pool[T]) lookup(key string) (*entry[T], bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	e, ok := p.entries[key]
+	return e, ok
+}
+
+// Get returns the shared resource for key, building it with factory if it
+// doesn't already exist (or its generation was retired by a failed
+// HealthCheck), along with a closer the caller must call exactly once when
+// done. The resource is only actually closed (via OnEvict) once every
+// borrower holding that generation has called its closer and, for the
+// current generation, IdleTimeout has elapsed or MaxIdle evicts it sooner.
+func (p *Pool[T]) Get(key string, factory func() (*T, error), opts ...Option[T]) (*T, func(), error) {
+	p.mu.Lock()
+	e, ok := p.entries[key]
+	if !ok {
+		e = &entry[T]{key: key, pool: p}
+		p.entries[key] = e
+	}
+	p.mu.Unlock()
+
+	e.mu.Lock()
+
+	for _, opt := range opts {
+		opt(&e.cfg)
+	}
+
+	if e.current != nil && e.cfg.healthCheck != nil {
+		if err := e.cfg.healthCheck(e.current.instance); err != nil {
+			stale := e.current
+			stale.stale = true
+			if e.timer != nil {
+				e.timer.Stop()
+				e.timer = nil
+			}
+			e.current = nil
+			e.buildOnce = nil
+			if stale.refs <= 0 {
+				// Nobody is holding it, so it's safe to tear down right away.
+				instance := stale.instance
+				stale.instance = nil
+				if e.cfg.onEvict != nil {
+					e.cfg.onEvict(instance)
+				}
+			}
+			// Otherwise its last remaining borrower's closer tears it down.
+		}
+	}
+
+	if e.current == nil {
+		if e.buildOnce == nil {
+			e.buildOnce = &sync.Once{}
+		}
+		e.buildOnce.Do(func() {
+			instance, err := factory()
+			if err != nil {
+				e.buildErr = err
+				return
+			}
+			e.current = &generation[T]{instance: instance}
+		})
+		if e.buildErr != nil {
+			err := e.buildErr
+			e.buildOnce = nil // let the next borrower retry instead of reusing a failed generation
+			e.buildErr = nil
+			e.mu.Unlock()
+			return nil, nil, err
+		}
+	} else if e.timer != nil {
+		// Reused from the idle cache; cancel its pending eviction.
+		e.timer.Stop()
+		e.timer = nil
+	}
+
+	g := e.current
+	g.refs++
+	instance := g.instance
+	e.mu.Unlock()
+	p.unmarkIdle(key)
+
+	var released bool
+	closer := func() {
+		e.mu.Lock()
+		if released {
+			e.mu.Unlock()
+			return
+		}
+		released = true
+		g.refs--
+
+		if g.stale {
+			var onEvict func(*T)
+			var instance *T
+			if g.refs <= 0 && g.instance != nil {
+				instance = g.instance
+				g.instance = nil
+				onEvict = e.cfg.onEvict
+			}
+			e.mu.Unlock()
+			if onEvict != nil {
+				onEvict(instance)
+			}
+			return
+		}
+
+		idle := g.refs <= 0
+		var maxIdle int
+		if idle {
+			e.scheduleEvictionLocked()
+			maxIdle = e.cfg.maxIdle
+		}
+		e.mu.Unlock()
+		if idle {
+			p.markIdle(key, maxIdle)
+		}
+	}
+	return instance, closer, nil
+}
+
+// pools holds one *Pool[T] per distinct T, created lazily so the
+// package-level Get function below can be generic without a package-level
+// generic variable, which Go does not allow.
+var pools sync.Map // reflect.Type -> any (*Pool[T])
+
+func poolFor[T any]() *Pool[T] {
+	key := reflect.TypeOf((*T)(nil))
+	raw, _ := pools.LoadOrStore(key, &Pool[T]{entries: make(map[string]*entry[T])})
+	return raw.(*Pool[T])
+}
+
+// Get returns the shared, refcounted resource for key from the
+// package-wide Pool[T], building it with factory on first use (or after
+// eviction). See Pool.Get for the full eviction and health-check
+// semantics.
+func Get[T any](key string, factory func() (*T, error), opts ...Option[T]) (*T, func(), error) {
+	return poolFor[T]().Get(key, factory, opts...)
+}
@@ -0,0 +1,190 @@
+package pool
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type conn struct{ id int }
+
+func TestGetReturnsSharedInstanceUntilAllClosersRun(t *testing.T) {
+	var builds int
+	factory := func() (*conn, error) {
+		builds++
+		return &conn{id: builds}, nil
+	}
+
+	a, closeA, err := Get("refcount", factory)
+	if err != nil {
+		t.Fatalf("Get error = %v", err)
+	}
+	b, closeB, err := Get("refcount", factory)
+	if err != nil {
+		t.Fatalf("Get error = %v", err)
+	}
+	if a != b {
+		t.Fatalf("Get returned different instances for the same key while both are in use")
+	}
+	if builds != 1 {
+		t.Fatalf("factory ran %d times, want 1", builds)
+	}
+
+	closeA()
+	// Still referenced by b, so a second Get must not rebuild.
+	c, closeC, err := Get("refcount", factory)
+	if err != nil {
+		t.Fatalf("Get error = %v", err)
+	}
+	if c != a {
+		t.Fatalf("Get rebuilt the resource while it still had a live borrower")
+	}
+	closeB()
+	closeC()
+}
+
+func TestHealthCheckFailureTriggersReconstruction(t *testing.T) {
+	var builds int32
+	var evictions int32
+	factory := func() (*conn, error) {
+		n := atomic.AddInt32(&builds, 1)
+		return &conn{id: int(n)}, nil
+	}
+
+	// IdleTimeout keeps the instance cached across Get calls so the second
+	// call's HealthCheck actually runs against it instead of finding a
+	// freshly evicted, empty entry.
+	first, closeFirst, err := Get("healthcheck", factory, IdleTimeout[conn](time.Second))
+	if err != nil {
+		t.Fatalf("Get error = %v", err)
+	}
+	closeFirst()
+
+	second, closeSecond, err := Get("healthcheck", factory,
+		IdleTimeout[conn](time.Second),
+		HealthCheck(func(*conn) error { return errors.New("unhealthy") }),
+		OnEvict(func(*conn) { atomic.AddInt32(&evictions, 1) }),
+	)
+	if err != nil {
+		t.Fatalf("Get error = %v", err)
+	}
+	defer closeSecond()
+
+	if second == first {
+		t.Fatalf("Get returned the unhealthy instance instead of rebuilding")
+	}
+	if got := atomic.LoadInt32(&builds); got != 2 {
+		t.Fatalf("factory ran %d times, want 2", got)
+	}
+	if got := atomic.LoadInt32(&evictions); got != 1 {
+		t.Fatalf("OnEvict ran %d times, want 1", got)
+	}
+}
+
+func TestHealthCheckFailureDoesNotEvictWhileStillReferenced(t *testing.T) {
+	var evictions int32
+	first, closeFirst, err := Get("healthcheck-live", func() (*conn, error) { return &conn{id: 1}, nil },
+		IdleTimeout[conn](time.Second),
+	)
+	if err != nil {
+		t.Fatalf("Get error = %v", err)
+	}
+	// first is never closed during this test: it's still a live borrower
+	// when the HealthCheck below fails, so OnEvict must not run yet.
+
+	second, closeSecond, err := Get("healthcheck-live", func() (*conn, error) { return &conn{id: 2}, nil },
+		IdleTimeout[conn](time.Second),
+		HealthCheck(func(*conn) error { return errors.New("unhealthy") }),
+		OnEvict(func(*conn) { atomic.AddInt32(&evictions, 1) }),
+	)
+	if err != nil {
+		t.Fatalf("Get error = %v", err)
+	}
+	if second == first {
+		t.Fatalf("Get returned the unhealthy instance instead of a fresh generation")
+	}
+	if got := atomic.LoadInt32(&evictions); got != 0 {
+		t.Fatalf("evictions = %d while the retired generation still has a live borrower, want 0", got)
+	}
+
+	closeSecond()
+	closeFirst() // releases the last borrower of the retired generation
+	if got := atomic.LoadInt32(&evictions); got != 1 {
+		t.Fatalf("evictions = %d after the retired generation's last borrower released it, want 1", got)
+	}
+}
+
+func TestIdleTimeoutEvictsUnreferencedResource(t *testing.T) {
+	var evicted int32
+	instance, closer, err := Get("idle-timeout", func() (*conn, error) { return &conn{id: 1}, nil },
+		IdleTimeout[conn](10*time.Millisecond),
+		OnEvict(func(*conn) { atomic.AddInt32(&evicted, 1) }),
+	)
+	if err != nil {
+		t.Fatalf("Get error = %v", err)
+	}
+	_ = instance
+	closer()
+
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&evicted) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := atomic.LoadInt32(&evicted); got != 1 {
+		t.Fatalf("evicted = %d after IdleTimeout elapsed, want 1", got)
+	}
+}
+
+func TestMaxIdleEvictsLeastRecentlyIdleKey(t *testing.T) {
+	var mu sync.Mutex
+	var evictedIDs []int
+	opt := MaxIdle[conn](1)
+	onEvict := OnEvict(func(c *conn) {
+		mu.Lock()
+		defer mu.Unlock()
+		evictedIDs = append(evictedIDs, c.id)
+	})
+
+	_, closeA, err := Get("maxidle-a", func() (*conn, error) { return &conn{id: 1}, nil }, opt, onEvict)
+	if err != nil {
+		t.Fatalf("Get error = %v", err)
+	}
+	closeA() // key "a" becomes idle; still within the cap of 1
+
+	_, closeB, err := Get("maxidle-b", func() (*conn, error) { return &conn{id: 2}, nil }, opt, onEvict)
+	if err != nil {
+		t.Fatalf("Get error = %v", err)
+	}
+	closeB() // key "b" becomes idle too, pushing the idle count to 2 > MaxIdle(1)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(evictedIDs) != 1 || evictedIDs[0] != 1 {
+		t.Fatalf("evicted ids = %v, want [1] (the least-recently-idle key evicted by MaxIdle)", evictedIDs)
+	}
+}
+
+// TestConcurrentOptsGetAndCloserIsRaceFree exercises Get and a closer
+// racing on the same key while options (including MaxIdle) are applied on
+// every Get call, the scenario the data race around e.cfg.maxIdle needed
+// a concurrent opts-Get + closer pair on one entry to surface under
+// -race.
+func TestConcurrentOptsGetAndCloserIsRaceFree(t *testing.T) {
+	factory := func() (*conn, error) { return &conn{id: 1}, nil }
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, closer, err := Get("concurrent-opts", factory, MaxIdle[conn](2), IdleTimeout[conn](time.Millisecond))
+			if err != nil {
+				return
+			}
+			closer()
+		}()
+	}
+	wg.Wait()
+}
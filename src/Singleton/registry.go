@@ -0,0 +1,116 @@
+package singleton
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// resettableOnce behaves like sync.Once except that a failed attempt does
+// not permanently lock the slot: if f returns an error, the once is
+// re-armed so the next call retries construction instead of being stuck
+// with the first error forever.
+type resettableOnce struct {
+	mu   sync.Mutex
+	done bool
+}
+
+func (o *resettableOnce) do(f func() error) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.done {
+		return nil
+	}
+	if err := f(); err != nil {
+		return err
+	}
+	o.done = true
+	return nil
+}
+
+// onceEntry holds the lazily-constructed value for one registry key.
+// instance is an atomic.Pointer rather than a plain *T so Get can read it
+// without taking once's lock, even while another goroutine is inside
+// RegisterE building it.
+type onceEntry[T any] struct {
+	once     resettableOnce
+	instance atomic.Pointer[T]
+}
+
+// Registry manages multiple named singletons of type T, each constructed
+// at most once no matter how many goroutines race to create it. Keys are
+// independent: building "a" never blocks building "b".
+type Registry[T any] struct {
+	entries sync.Map // string -> *onceEntry[T]
+}
+
+// Register lazily constructs and caches the singleton for key, invoking
+// build at most once. Later calls with the same key return the cached
+// instance without invoking build again.
+func (r *Registry[T]) Register(key string, build func() *T) *T {
+	instance, _ := r.RegisterE(key, func() (*T, error) {
+		return build(), nil
+	})
+	return instance
+}
+
+// RegisterE is like Register but allows build to fail. A failed build does
+// not poison key: the next call for the same key retries construction
+// rather than returning the first error forever.
+func (r *Registry[T]) RegisterE(key string, build func() (*T, error)) (*T, error) {
+	raw, _ := r.entries.LoadOrStore(key, &onceEntry[T]{})
+	entry := raw.(*onceEntry[T])
+
+	if err := entry.once.do(func() error {
+		instance, err := build()
+		if err != nil {
+			return err
+		}
+		entry.instance.Store(instance)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return entry.instance.Load(), nil
+}
+
+// Get returns the singleton previously stored under key, or nil if key has
+// not been registered yet.
+func (r *Registry[T]) Get(key string) *T {
+	raw, ok := r.entries.Load(key)
+	if !ok {
+		return nil
+	}
+	return raw.(*onceEntry[T]).instance.Load()
+}
+
+// registries holds one *Registry[T] per distinct T, created lazily so the
+// package-level Register/RegisterE/Get functions below can be generic
+// without a package-level generic variable, which Go does not allow.
+var registries sync.Map // reflect.Type -> any (*Registry[T])
+
+func registryFor[T any]() *Registry[T] {
+	key := reflect.TypeOf((*T)(nil))
+	raw, _ := registries.LoadOrStore(key, &Registry[T]{})
+	return raw.(*Registry[T])
+}
+
+// Register lazily constructs and caches a singleton of type T under key,
+// using a package-wide Registry[T] shared by every caller. It is the
+// generic, keyed counterpart to GetInstance for when a program needs more
+// than one named singleton of the same type (e.g. several named DB pools).
+func Register[T any](key string, build func() *T) *T {
+	return registryFor[T]().Register(key, build)
+}
+
+// RegisterE is the error-returning counterpart of Register; see
+// Registry.RegisterE for how a failed build leaves the slot retryable.
+func RegisterE[T any](key string, build func() (*T, error)) (*T, error) {
+	return registryFor[T]().RegisterE(key, build)
+}
+
+// Get returns the singleton previously registered under key for type T, or
+// nil if it has not been registered yet.
+func Get[T any](key string) *T {
+	return registryFor[T]().Get(key)
+}
@@ -0,0 +1,68 @@
+package singleton
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+type widget struct{ n int }
+
+func TestRegisterReturnsCachedInstance(t *testing.T) {
+	var calls int
+	first := Register("registry-test-widget", func() *widget {
+		calls++
+		return &widget{n: calls}
+	})
+	second := Register("registry-test-widget", func() *widget {
+		calls++
+		return &widget{n: calls}
+	})
+
+	if first != second {
+		t.Fatalf("Register returned different instances for the same key")
+	}
+	if calls != 1 {
+		t.Fatalf("build ran %d times, want 1", calls)
+	}
+}
+
+func TestRegisterEDoesNotPoisonKeyOnError(t *testing.T) {
+	key := "registry-test-flaky"
+	boom := errors.New("boom")
+
+	_, err := RegisterE(key, func() (*widget, error) {
+		return nil, boom
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("first RegisterE error = %v, want %v", err, boom)
+	}
+
+	instance, err := RegisterE(key, func() (*widget, error) {
+		return &widget{n: 99}, nil
+	})
+	if err != nil {
+		t.Fatalf("retry RegisterE error = %v, want nil", err)
+	}
+	if instance == nil || instance.n != 99 {
+		t.Fatalf("retry RegisterE instance = %+v, want n=99", instance)
+	}
+}
+
+func TestRegisterConcurrentAccessIsRaceFree(t *testing.T) {
+	key := "registry-test-concurrent"
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			Register(key, func() *widget { return &widget{n: 1} })
+		}()
+		go func() {
+			defer wg.Done()
+			Get[widget](key)
+		}()
+	}
+	wg.Wait()
+}
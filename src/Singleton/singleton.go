@@ -1,25 +1,65 @@
-package main
+// Package singleton demonstrates the classic singleton pattern along with
+// a generic keyed variant for managing more than one named instance (see
+// registry.go).
+package singleton
 
 import (
-	"fmt"
 	"sync"
+	"sync/atomic"
 )
 
+// Singleton is the classic single-instance type returned by GetInstance.
 type Singleton struct{}
 
-var instance *Singleton
-var once sync.Once
+// Holder bundles the sync.Once guarding GetInstance's construction with the
+// instance it guards. It is exported only so the singleton/testing
+// subpackage can swap both atomically for mocks; production code should
+// always go through GetInstance instead of touching a Holder directly.
+type Holder struct {
+	once     *sync.Once
+	instance *Singleton
+}
 
-func GetInstance() *Singleton {
-	once.Do(func() {
-		instance = &Singleton{}
-	})
-	return instance
+// NewHolder returns a Holder wrapping instance. Pass nil to get a holder
+// that will lazily construct a new Singleton the next time GetInstance is
+// called.
+func NewHolder(instance *Singleton) *Holder {
+	h := &Holder{once: &sync.Once{}, instance: instance}
+	if instance != nil {
+		h.once.Do(func() {})
+	}
+	return h
 }
 
-func main() {
-	singleton1 := GetInstance()
-	singleton2 := GetInstance()
+var current atomic.Pointer[Holder]
 
-	fmt.Println(singleton1 == singleton2)  // Output: true
+func init() {
+	current.Store(NewHolder(nil))
+}
+
+// CurrentHolder returns the Holder currently backing GetInstance.
+func CurrentHolder() *Holder {
+	return current.Load()
+}
+
+// SetHolder atomically installs h as the Holder backing GetInstance and
+// returns the one it replaced.
+func SetHolder(h *Holder) *Holder {
+	return current.Swap(h)
+}
+
+// GetInstance returns the single shared Singleton instance, constructing
+// it lazily on first call. Production code pays no locking cost beyond the
+// usual sync.Once check; swapping the Holder (via SetHolder, or the
+// singleton/testing subpackage) is only meant for tests.
+//
+//	a := GetInstance()
+//	b := GetInstance()
+//	a == b // true
+func GetInstance() *Singleton {
+	h := current.Load()
+	h.once.Do(func() {
+		h.instance = &Singleton{}
+	})
+	return h.instance
 }
@@ -0,0 +1,37 @@
+// Package testing provides test-only helpers for overriding the singleton
+// package's GetInstance result. The Go singleton pattern "reduces
+// testability" (there is no constructor to inject a mock into), so this
+// package exists to claw that back for tests without weakening the
+// production API.
+package testing
+
+import (
+	stdtesting "testing"
+
+	singleton "github.com/DevelopersToolbox/design-patterns/src/Singleton"
+)
+
+// Reset discards the current singleton instance so the next call to
+// GetInstance constructs a fresh one. Useful between table-driven test
+// cases that must not see each other's state.
+func Reset() {
+	singleton.SetHolder(singleton.NewHolder(nil))
+}
+
+// Override replaces the instance GetInstance returns with mock until the
+// returned restore func is called, which puts the previous instance back.
+func Override(mock *singleton.Singleton) (restore func()) {
+	previous := singleton.SetHolder(singleton.NewHolder(mock))
+	return func() {
+		singleton.SetHolder(previous)
+	}
+}
+
+// WithInstance installs mock as the singleton for the duration of the
+// current test, restoring the previous instance automatically via
+// t.Cleanup.
+func WithInstance(t *stdtesting.T, mock *singleton.Singleton) {
+	t.Helper()
+	restore := Override(mock)
+	t.Cleanup(restore)
+}
@@ -0,0 +1,61 @@
+package testing
+
+import (
+	stdtesting "testing"
+
+	singleton "github.com/DevelopersToolbox/design-patterns/src/Singleton"
+)
+
+// Singleton is a zero-size struct{}, so comparing *singleton.Singleton
+// pointers for identity is unreliable (Go may alias zero-size allocations
+// to the same address). These tests instead compare the *Holder returned
+// by CurrentHolder, which always has distinct identity per swap.
+
+func TestResetInstallsAFreshHolder(t *stdtesting.T) {
+	singleton.GetInstance() // make sure a holder is installed
+	before := singleton.CurrentHolder()
+
+	Reset()
+
+	after := singleton.CurrentHolder()
+	if before == after {
+		t.Fatal("Reset did not install a new Holder")
+	}
+	if singleton.GetInstance() == nil {
+		t.Fatal("GetInstance returned nil after Reset")
+	}
+}
+
+func TestOverrideSwapsAndRestoresTheHolder(t *stdtesting.T) {
+	before := singleton.CurrentHolder()
+
+	mock := &singleton.Singleton{}
+	restore := Override(mock)
+
+	during := singleton.CurrentHolder()
+	if during == before {
+		t.Fatal("Override did not swap in a new Holder")
+	}
+
+	restore()
+
+	after := singleton.CurrentHolder()
+	if after != before {
+		t.Fatal("restore did not put the original Holder back")
+	}
+}
+
+func TestWithInstanceRestoresAfterTest(t *stdtesting.T) {
+	before := singleton.CurrentHolder()
+
+	t.Run("sub", func(t *stdtesting.T) {
+		WithInstance(t, &singleton.Singleton{})
+		if singleton.CurrentHolder() == before {
+			t.Fatal("WithInstance did not swap in a new Holder")
+		}
+	})
+
+	if singleton.CurrentHolder() != before {
+		t.Fatal("WithInstance did not restore the original Holder after the subtest's Cleanup ran")
+	}
+}